@@ -18,8 +18,12 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"regexp"
+	"strings"
 	"text/tabwriter"
 	"text/template"
 
@@ -40,7 +44,10 @@ func newNetworkLsCommand() *cobra.Command {
 		SilenceErrors: true,
 	}
 	cmd.Flags().BoolP("quiet", "q", false, "Only display network IDs")
-	// Alias "-f" is reserved for "--filter"
+	cmd.Flags().StringArrayP("filter", "f", []string{}, "Filter matched networks")
+	cmd.Flags().StringArray("driver", []string{}, "Filter networks by driver (shorthand for --filter driver=)")
+	cmd.Flags().StringArray("config-path", []string{}, "Search additional CNI config directories, e.g. ones managed by k3s or kubelet (repeatable)")
+	cmd.Flags().Bool("no-trunc", false, "Do not truncate the output")
 	cmd.Flags().String("format", "", "Format the output using the given Go template, e.g, '{{json .}}'")
 	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"json", "table", "wide"}, cobra.ShellCompDirectiveNoFileComp
@@ -48,12 +55,47 @@ func newNetworkLsCommand() *cobra.Command {
 	return cmd
 }
 
+// matchesPseudoNetwork reports whether the "host"/"none" pseudo network
+// named name should be listed given the parsed filters. They have no ID,
+// labels, or driver, so this runs them through the same
+// netutil.FilterNetworks logic as real networks, against a synthetic
+// config with those fields left unset, instead of special-casing keys
+// here (which would get negated filters like "driver!=bridge" wrong).
+func matchesPseudoNetwork(name string, filters []netutil.Filter) bool {
+	synthetic := netutil.NetworkConfig{
+		NetworkConfigList: &netutil.NetworkConfigList{Name: name},
+	}
+	matched, err := netutil.FilterNetworks([]netutil.NetworkConfig{synthetic}, filters)
+	if err != nil {
+		return false
+	}
+	return len(matched) == 1
+}
+
+var tableFieldPattern = regexp.MustCompile(`{{\s*\.(\w+)\s*}}`)
+
+// tableHeader derives a "FIELD1\tFIELD2" header row from a row template
+// like "{{.Name}}\t{{.Driver}}", matching `docker ... ls --format 'table ...'`.
+func tableHeader(rowFormat string) string {
+	matches := tableFieldPattern.FindAllStringSubmatch(rowFormat, -1)
+	headers := make([]string, len(matches))
+	for i, m := range matches {
+		headers[i] = strings.ToUpper(m[1])
+	}
+	return strings.Join(headers, "\t")
+}
+
 type networkPrintable struct {
-	ID     string // empty for non-nerdctl networks
-	Name   string
-	Labels string
-	// TODO: "CreatedAt", "Driver", "IPv6", "Internal", "Scope"
-	file string `json:"-"`
+	ID         string // empty for non-nerdctl networks
+	Name       string
+	Labels     string
+	CreatedAt  string
+	Driver     string
+	IPv6       bool
+	Internal   bool
+	Scope      string
+	File       string
+	ConfigPath string
 }
 
 func networkLsAction(cmd *cobra.Command, args []string) error {
@@ -65,32 +107,75 @@ func networkLsAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	filterStrs, err := cmd.Flags().GetStringArray("filter")
+	if err != nil {
+		return err
+	}
+	drivers, err := cmd.Flags().GetStringArray("driver")
+	if err != nil {
+		return err
+	}
+	for _, d := range drivers {
+		filterStrs = append(filterStrs, "driver="+d)
+	}
+	filters, err := netutil.ParseFilters(filterStrs)
+	if err != nil {
+		return err
+	}
+	configPaths, err := cmd.Flags().GetStringArray("config-path")
+	if err != nil {
+		return err
+	}
+	noTrunc, err := cmd.Flags().GetBool("no-trunc")
+	if err != nil {
+		return err
+	}
 	w := cmd.OutOrStdout()
 	var tmpl *template.Template
+	asJSONArray := false
 	format, err := cmd.Flags().GetString("format")
 	if err != nil {
 		return err
 	}
-	switch format {
-	case "", "table", "wide":
+	switch {
+	case format == "" || format == "wide":
 		w = tabwriter.NewWriter(cmd.OutOrStdout(), 4, 8, 4, ' ', 0)
 		if !quiet {
 			fmt.Fprintln(w, "NETWORK ID\tNAME\tFILE")
 		}
-	case "raw":
+	case format == "raw":
 		return errors.New("unsupported format: \"raw\"")
+	case format == "json":
+		if quiet {
+			return errors.New("format and quiet must not be specified together")
+		}
+		asJSONArray = true
+	case format == "table" || strings.HasPrefix(format, "table "):
+		if quiet {
+			return errors.New("format and quiet must not be specified together")
+		}
+		rowFormat := strings.TrimPrefix(strings.TrimPrefix(format, "table"), " ")
+		if rowFormat == "" {
+			rowFormat = "{{.ID}}\t{{.Name}}\t{{.File}}"
+		}
+		w = tabwriter.NewWriter(cmd.OutOrStdout(), 4, 8, 4, ' ', 0)
+		fmt.Fprintln(w, tableHeader(rowFormat))
+		tmpl, err = formatter.ParseTemplate(rowFormat)
+		if err != nil {
+			return err
+		}
 	default:
 		if quiet {
 			return errors.New("format and quiet must not be specified together")
 		}
-		var err error
 		tmpl, err = formatter.ParseTemplate(format)
 		if err != nil {
 			return err
 		}
 	}
 
-	e, err := netutil.NewCNIEnv(globalOptions.CNIPath, globalOptions.CNINetConfPath)
+	netConfPaths := append([]string{globalOptions.CNINetConfPath}, configPaths...)
+	e, err := netutil.NewCNIEnvWithPaths(globalOptions.CNIPath, netConfPaths)
 	if err != nil {
 		return err
 	}
@@ -98,15 +183,27 @@ func networkLsAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	netConfigs, err = netutil.FilterNetworks(netConfigs, filters)
+	if err != nil {
+		return err
+	}
 	pp := make([]networkPrintable, len(netConfigs))
 	for i, n := range netConfigs {
 		p := networkPrintable{
-			Name: n.Name,
-			file: n.File,
+			Name:       n.Name,
+			Driver:     n.Driver(),
+			IPv6:       n.IPv6(),
+			Internal:   n.Internal(),
+			Scope:      n.Scope(),
+			File:       n.File,
+			ConfigPath: n.Dir,
+		}
+		if st, err := os.Stat(n.File); err == nil {
+			p.CreatedAt = st.ModTime().String()
 		}
 		if n.NerdctlID != nil {
 			p.ID = *n.NerdctlID
-			if len(p.ID) > 12 {
+			if !noTrunc && len(p.ID) > 12 {
 				p.ID = p.ID[:12]
 			}
 		}
@@ -116,15 +213,22 @@ func networkLsAction(cmd *cobra.Command, args []string) error {
 		pp[i] = p
 	}
 
-	// append pseudo networks
-	pp = append(pp, []networkPrintable{
-		{
-			Name: "host",
-		},
-		{
-			Name: "none",
-		},
-	}...)
+	// append pseudo networks, unless a filter was given that they can't satisfy
+	// (e.g. "id=...", "label=...", "driver=bridge" never match "host"/"none")
+	for _, name := range []string{"host", "none"} {
+		if matchesPseudoNetwork(name, filters) {
+			pp = append(pp, networkPrintable{Name: name})
+		}
+	}
+
+	if asJSONArray {
+		b, err := json.MarshalIndent(pp, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
 
 	for _, p := range pp {
 		if tmpl != nil {
@@ -140,7 +244,7 @@ func networkLsAction(cmd *cobra.Command, args []string) error {
 				fmt.Fprintln(w, p.ID)
 			}
 		} else {
-			fmt.Fprintf(w, "%s\t%s\t%s\n", p.ID, p.Name, p.file)
+			fmt.Fprintf(w, "%s\t%s\t%s\n", p.ID, p.Name, p.File)
 		}
 	}
 	if f, ok := w.(formatter.Flusher); ok {
@@ -0,0 +1,202 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd"
+
+	"github.com/containerd/nerdctl/pkg/labels"
+	"github.com/containerd/nerdctl/pkg/lockutil"
+	"github.com/containerd/nerdctl/pkg/netutil"
+
+	"github.com/spf13/cobra"
+)
+
+func newNetworkPruneCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "prune [flags]",
+		Short:         "Remove all unused networks",
+		Args:          cobra.NoArgs,
+		RunE:          networkPruneAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().BoolP("force", "f", false, "Do not prompt for confirmation")
+	cmd.Flags().StringArray("filter", []string{}, "Provide filter values (e.g. \"until=<duration>\")")
+	return cmd
+}
+
+func networkPruneAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+	if !force {
+		var confirm string
+		msg := "This will remove all networks not used by at least one container."
+		fmt.Fprintf(cmd.OutOrStdout(), "WARNING! %s\n", msg)
+		fmt.Fprint(cmd.OutOrStdout(), "Are you sure you want to continue? [y/N] ")
+		fmt.Fscanf(cmd.InOrStdin(), "%s", &confirm)
+		if strings.ToLower(confirm) != "y" {
+			return nil
+		}
+	}
+	filterStrs, err := cmd.Flags().GetStringArray("filter")
+	if err != nil {
+		return err
+	}
+	until, labelFilters, err := parsePruneFilters(filterStrs)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := newClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	// Take the same lock `network create` holds while writing a conflist,
+	// and hold it across both the in-use snapshot and the removal loop, so
+	// a concurrent `nerdctl run` can't attach to a network after we've
+	// decided it's unused but before we've deleted its conflist.
+	lock, err := lockutil.Lock(globalOptions.CNINetConfPath)
+	if err != nil {
+		return err
+	}
+	defer lockutil.Unlock(lock)
+
+	inUse, err := networksInUse(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	e, err := netutil.NewCNIEnv(globalOptions.CNIPath, globalOptions.CNINetConfPath)
+	if err != nil {
+		return err
+	}
+	netConfigs, err := e.NetworkList()
+	if err != nil {
+		return err
+	}
+
+	var removed []string
+	for _, n := range netConfigs {
+		if n.NerdctlID == nil {
+			// not managed by nerdctl; never prune
+			continue
+		}
+		if inUse[n.Name] {
+			continue
+		}
+		if !matchesPruneFilters(n, until, labelFilters) {
+			continue
+		}
+		if err := os.Remove(n.File); err != nil {
+			return fmt.Errorf("failed to remove network config %q: %w", n.File, err)
+		}
+		removed = append(removed, n.Name)
+	}
+
+	if len(removed) > 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "Deleted Networks:")
+		for _, name := range removed {
+			fmt.Fprintln(cmd.OutOrStdout(), name)
+		}
+	}
+	return nil
+}
+
+// networksInUse returns the set of network names that at least one
+// container (running or not) is currently attached to.
+func networksInUse(ctx context.Context, client *containerd.Client) (map[string]bool, error) {
+	containers, err := client.Containers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inUse := make(map[string]bool)
+	for _, c := range containers {
+		l, err := c.Labels(ctx)
+		if err != nil {
+			continue
+		}
+		raw, ok := l[labels.Networks]
+		if !ok {
+			continue
+		}
+		var names []string
+		if err := json.Unmarshal([]byte(raw), &names); err != nil {
+			continue
+		}
+		for _, name := range names {
+			inUse[name] = true
+		}
+	}
+	return inUse, nil
+}
+
+func parsePruneFilters(filters []string) (time.Duration, []netutil.Filter, error) {
+	var until time.Duration
+	var labelFilters []string
+	for _, f := range filters {
+		if strings.HasPrefix(f, "until=") {
+			d, err := time.ParseDuration(strings.TrimPrefix(f, "until="))
+			if err != nil {
+				return 0, nil, fmt.Errorf("invalid until filter %q: %w", f, err)
+			}
+			until = d
+			continue
+		}
+		if strings.HasPrefix(f, "label=") || strings.HasPrefix(f, "label!=") {
+			labelFilters = append(labelFilters, f)
+			continue
+		}
+		return 0, nil, fmt.Errorf("invalid filter %q", f)
+	}
+	parsed, err := netutil.ParseFilters(labelFilters)
+	if err != nil {
+		return 0, nil, err
+	}
+	return until, parsed, nil
+}
+
+func matchesPruneFilters(n netutil.NetworkConfig, until time.Duration, labelFilters []netutil.Filter) bool {
+	if until > 0 {
+		st, err := os.Stat(n.File)
+		if err != nil || time.Since(st.ModTime()) < until {
+			return false
+		}
+	}
+	if len(labelFilters) > 0 {
+		matched, err := netutil.FilterNetworks([]netutil.NetworkConfig{n}, labelFilters)
+		if err != nil || len(matched) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containerd/nerdctl/pkg/netutil"
+)
+
+func TestParsePruneFilters(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name       string
+		filters    []string
+		wantUntil  time.Duration
+		wantLabels int
+		wantErr    bool
+	}{
+		{name: "empty", filters: nil},
+		{name: "until only", filters: []string{"until=1h"}, wantUntil: time.Hour},
+		{name: "label only", filters: []string{"label=foo=bar"}, wantLabels: 1},
+		{name: "until and label", filters: []string{"until=30m", "label=foo"}, wantUntil: 30 * time.Minute, wantLabels: 1},
+		{name: "bad until errors", filters: []string{"until=notaduration"}, wantErr: true},
+		{name: "unsupported key errors", filters: []string{"name=foo"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			until, labelFilters, err := parsePruneFilters(tc.filters)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if until != tc.wantUntil {
+				t.Errorf("until = %v, want %v", until, tc.wantUntil)
+			}
+			if len(labelFilters) != tc.wantLabels {
+				t.Errorf("got %d label filters, want %d", len(labelFilters), tc.wantLabels)
+			}
+		})
+	}
+}
+
+func TestMatchesPruneFilters(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "old.conflist")
+	if err := os.WriteFile(file, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(file, old, old); err != nil {
+		t.Fatal(err)
+	}
+	labels := map[string]string{"foo": "bar"}
+	n := netutil.NetworkConfig{
+		NetworkConfigList: &netutil.NetworkConfigList{Name: "old"},
+		NerdctlLabels:     &labels,
+		File:              file,
+	}
+
+	cases := []struct {
+		name         string
+		until        time.Duration
+		labelFilters []string
+		want         bool
+	}{
+		{name: "no filters matches", want: true},
+		{name: "older than until matches", until: time.Hour, want: true},
+		{name: "younger than until excludes", until: 24 * time.Hour, want: false},
+		{name: "matching label matches", labelFilters: []string{"label=foo=bar"}, want: true},
+		{name: "non-matching label excludes", labelFilters: []string{"label=foo=nope"}, want: false},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			parsed, err := netutil.ParseFilters(tc.labelFilters)
+			if err != nil {
+				t.Fatalf("ParseFilters: %v", err)
+			}
+			if got := matchesPruneFilters(n, tc.until, parsed); got != tc.want {
+				t.Errorf("matchesPruneFilters() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package formatter provides helpers shared by the `nerdctl * ls --format`
+// implementations.
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+func marshalJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Flusher is implemented by writers (e.g. *tabwriter.Writer) that need to be
+// flushed once all rows have been written.
+type Flusher interface {
+	Flush() error
+}
+
+// ParseTemplate parses a Go template given as a `--format` value, adding the
+// `json` template func used by Docker/Podman-style `{{json .}}` formats.
+func ParseTemplate(format string) (*template.Template, error) {
+	return template.New("").Funcs(template.FuncMap{"json": marshalJSON}).Parse(format)
+}
+
+// FormatLabels renders a label map as a sorted, comma-separated
+// "key=value" list, matching `docker inspect`-style label formatting.
+func FormatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, labels[k]))
+	}
+	return strings.Join(parts, ",")
+}
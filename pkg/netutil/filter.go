@@ -0,0 +1,183 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package netutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Filter is a single parsed `-f/--filter` predicate, e.g. "name=foo" or
+// "label!=com.example.foo=bar".
+type Filter struct {
+	Key      string
+	Value    string
+	Negate   bool
+	HasValue bool
+}
+
+// ParseFilters parses a slice of "key=value" / "key!=value" strings, as
+// accepted by `nerdctl network ls --filter`.
+func ParseFilters(filters []string) ([]Filter, error) {
+	parsed := make([]Filter, len(filters))
+	for i, f := range filters {
+		negate := false
+		kv := strings.SplitN(f, "!=", 2)
+		if len(kv) == 2 {
+			negate = true
+		} else {
+			kv = strings.SplitN(f, "=", 2)
+		}
+		if len(kv) == 0 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid filter %q", f)
+		}
+		switch kv[0] {
+		case "name", "label", "id", "driver":
+		default:
+			return nil, fmt.Errorf("invalid filter field %q", kv[0])
+		}
+		p := Filter{Key: kv[0], Negate: negate}
+		if len(kv) == 2 {
+			p.Value = kv[1]
+			p.HasValue = true
+		}
+		parsed[i] = p
+	}
+	return parsed, nil
+}
+
+// FilterNetworks returns the subset of configs that match every parsed
+// filter. Filter keys are ANDed together; multiple filters with the same
+// key are ORed, matching Docker's filter semantics.
+func FilterNetworks(configs []NetworkConfig, filters []Filter) ([]NetworkConfig, error) {
+	if len(filters) == 0 {
+		return configs, nil
+	}
+	grouped := make(map[string][]Filter)
+	for _, f := range filters {
+		grouped[f.Key] = append(grouped[f.Key], f)
+	}
+	var result []NetworkConfig
+	for _, c := range configs {
+		matched := true
+		for key, fs := range grouped {
+			if !matchesAny(c, fs, key) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func matchesAny(c NetworkConfig, fs []Filter, key string) bool {
+	for _, f := range fs {
+		m := matchesOne(c, f)
+		if f.Negate {
+			m = !m
+		}
+		if m {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesOne(c NetworkConfig, f Filter) bool {
+	switch f.Key {
+	case "name":
+		return strings.Contains(c.Name, f.Value)
+	case "id":
+		return c.NerdctlID != nil && strings.HasPrefix(*c.NerdctlID, f.Value)
+	case "label":
+		if c.NerdctlLabels == nil {
+			return false
+		}
+		// f.Value is itself "key" or "key=value" (the filter's own "="
+		// was already consumed splitting "label=..." in ParseFilters),
+		// so split it again rather than relying on f.HasValue, which is
+		// always true here.
+		kv := strings.SplitN(f.Value, "=", 2)
+		if len(kv) == 1 {
+			_, ok := (*c.NerdctlLabels)[kv[0]]
+			return ok
+		}
+		v, ok := (*c.NerdctlLabels)[kv[0]]
+		return ok && v == kv[1]
+	case "driver":
+		return c.Driver() == f.Value
+	default:
+		return false
+	}
+}
+
+// Driver returns the CNI plugin type of the first non-meta plugin in the
+// conflist, e.g. "bridge" or "macvlan".
+func (c NetworkConfig) Driver() string {
+	for _, p := range c.Plugins {
+		switch p.Type {
+		case "tuning", "firewall", "portmap":
+			continue
+		default:
+			return p.Type
+		}
+	}
+	return ""
+}
+
+// IPv6 reports whether any plugin's IPAM config has an IPv6 subnet.
+func (c NetworkConfig) IPv6() bool {
+	for _, p := range c.Plugins {
+		if p.IPAM == nil {
+			continue
+		}
+		for _, rs := range p.IPAM.Ranges {
+			for _, r := range rs {
+				if strings.Contains(r.Subnet, ":") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Internal reports whether the network has no outbound connectivity, i.e.
+// none of its plugins masquerade traffic or set up port forwarding.
+func (c NetworkConfig) Internal() bool {
+	for _, p := range c.Plugins {
+		if p.Type == "portmap" {
+			return false
+		}
+		if p.IPMasq != nil && *p.IPMasq {
+			return false
+		}
+	}
+	return true
+}
+
+// Scope returns the network's CNI scope. nerdctl only ever creates
+// host-local networks.
+func (c NetworkConfig) Scope() string {
+	if c.NerdctlID != nil {
+		return "local"
+	}
+	return ""
+}
@@ -0,0 +1,202 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package netutil
+
+import "testing"
+
+func TestParseFilters(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		filters []string
+		want    []Filter
+		wantErr bool
+	}{
+		{
+			name:    "simple key=value",
+			filters: []string{"name=foo"},
+			want:    []Filter{{Key: "name", Value: "foo", HasValue: true}},
+		},
+		{
+			name:    "negated key!=value",
+			filters: []string{"driver!=bridge"},
+			want:    []Filter{{Key: "driver", Value: "bridge", HasValue: true, Negate: true}},
+		},
+		{
+			name:    "label with no value",
+			filters: []string{"label=com.example.foo"},
+			want:    []Filter{{Key: "label", Value: "com.example.foo", HasValue: true}},
+		},
+		{
+			name:    "unknown key errors",
+			filters: []string{"until=1h"},
+			wantErr: true,
+		},
+		{
+			name:    "empty key errors",
+			filters: []string{"=foo"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseFilters(tc.filters)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d filters, want %d", len(got), len(tc.want))
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("filter %d = %+v, want %+v", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestFilterNetworks(t *testing.T) {
+	t.Parallel()
+	labels := map[string]string{"com.example.foo": "bar"}
+	configs := []NetworkConfig{
+		{
+			NetworkConfigList: &NetworkConfigList{Name: "bridge", Plugins: []CNIPlugin{{Type: "bridge"}}},
+			NerdctlID:         strPtr("aaaaaaaaaaaa1111"),
+			NerdctlLabels:     &labels,
+		},
+		{
+			NetworkConfigList: &NetworkConfigList{Name: "macvlannet", Plugins: []CNIPlugin{{Type: "macvlan"}}},
+			NerdctlID:         strPtr("bbbbbbbbbbbb2222"),
+		},
+	}
+
+	cases := []struct {
+		name    string
+		filters []string
+		want    []string // expected network names, in order
+	}{
+		{
+			name:    "no filters returns everything",
+			filters: nil,
+			want:    []string{"bridge", "macvlannet"},
+		},
+		{
+			name:    "name substring match",
+			filters: []string{"name=net"},
+			want:    []string{"macvlannet"},
+		},
+		{
+			name:    "driver match",
+			filters: []string{"driver=bridge"},
+			want:    []string{"bridge"},
+		},
+		{
+			name:    "negated driver excludes match",
+			filters: []string{"driver!=bridge"},
+			want:    []string{"macvlannet"},
+		},
+		{
+			name:    "id prefix match",
+			filters: []string{"id=aaaa"},
+			want:    []string{"bridge"},
+		},
+		{
+			name:    "label key only",
+			filters: []string{"label=com.example.foo"},
+			want:    []string{"bridge"},
+		},
+		{
+			name:    "label key=value",
+			filters: []string{"label=com.example.foo=bar"},
+			want:    []string{"bridge"},
+		},
+		{
+			name:    "label mismatch excludes",
+			filters: []string{"label=com.example.foo=nope"},
+			want:    nil,
+		},
+		{
+			name:    "same key ORs together",
+			filters: []string{"name=bridge", "name=macvlannet"},
+			want:    []string{"bridge", "macvlannet"},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			parsed, err := ParseFilters(tc.filters)
+			if err != nil {
+				t.Fatalf("ParseFilters: %v", err)
+			}
+			got, err := FilterNetworks(configs, parsed)
+			if err != nil {
+				t.Fatalf("FilterNetworks: %v", err)
+			}
+			var gotNames []string
+			for _, c := range got {
+				gotNames = append(gotNames, c.Name)
+			}
+			if len(gotNames) != len(tc.want) {
+				t.Fatalf("got names %v, want %v", gotNames, tc.want)
+			}
+			for i := range gotNames {
+				if gotNames[i] != tc.want[i] {
+					t.Errorf("got names %v, want %v", gotNames, tc.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestNetworkConfigInternal(t *testing.T) {
+	t.Parallel()
+	trueVal := true
+	falseVal := false
+	cases := []struct {
+		name    string
+		plugins []CNIPlugin
+		want    bool
+	}{
+		{name: "no ipMasq is internal", plugins: []CNIPlugin{{Type: "bridge"}}, want: true},
+		{name: "ipMasq false is internal", plugins: []CNIPlugin{{Type: "bridge", IPMasq: &falseVal}}, want: true},
+		{name: "ipMasq true is not internal", plugins: []CNIPlugin{{Type: "bridge", IPMasq: &trueVal}}, want: false},
+		{name: "portmap plugin is not internal", plugins: []CNIPlugin{{Type: "bridge"}, {Type: "portmap"}}, want: false},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			c := NetworkConfig{NetworkConfigList: &NetworkConfigList{Plugins: tc.plugins}}
+			if got := c.Internal(); got != tc.want {
+				t.Errorf("Internal() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package netutil provides utilities for handling CNI network configurations
+// that back `nerdctl network` subcommands.
+package netutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// CNIEnv is the configuration corresponding to $CNI_PATH and
+// $NETCONFPATH, defined in https://github.com/containernetworking/cni/blob/master/SPEC.md
+//
+// NetconfPath is kept for backwards compatibility and is always
+// NetconfPaths[0]; callers that only ever dealt with a single config root
+// (e.g. `network prune`, which writes to it) can keep using it.
+type CNIEnv struct {
+	Path         string
+	NetconfPath  string
+	NetconfPaths []string
+}
+
+// NewCNIEnv creates a new CNIEnv rooted at a single CNI config directory.
+func NewCNIEnv(cniPath, cniConfPath string) (*CNIEnv, error) {
+	return NewCNIEnvWithPaths(cniPath, []string{cniConfPath})
+}
+
+// NewCNIEnvWithPaths creates a new CNIEnv that searches each of the given
+// CNI config directories, e.g. those passed via repeated `--config-path`
+// flags. Entries are used as given; they are not split on ':'. The first
+// path is created if missing (it's where nerdctl writes its own networks);
+// the rest are assumed to be managed by other CNI consumers and are only
+// read from.
+func NewCNIEnvWithPaths(cniPath string, cniConfPaths []string) (*CNIEnv, error) {
+	if len(cniConfPaths) == 0 {
+		return nil, errors.New("at least one CNI config path is required")
+	}
+	e := &CNIEnv{
+		Path:         cniPath,
+		NetconfPath:  cniConfPaths[0],
+		NetconfPaths: cniConfPaths,
+	}
+	if err := os.MkdirAll(e.NetconfPath, 0755); err != nil {
+		return e, err
+	}
+	return e, nil
+}
+
+// CNIPlugin is a single entry of the "plugins" array in a CNI conflist.
+type CNIPlugin struct {
+	Type string `json:"type"`
+	// IPMasq enables NAT for traffic leaving the network; an explicit
+	// "true" is how nerdctl-created bridge networks without "--internal"
+	// look, while its absence or "false" marks a network Internal unless the
+	// conflist also has a "portmap" plugin.
+	IPMasq *bool `json:"ipMasq,omitempty"`
+	// IPAM is only populated for the (usually first) plugin that configures IP addressing.
+	IPAM *struct {
+		Type   string `json:"type"`
+		Ranges [][]struct {
+			Subnet string `json:"subnet"`
+		} `json:"ranges,omitempty"`
+	} `json:"ipam,omitempty"`
+}
+
+// NetworkConfigList is the subset of a CNI conflist that nerdctl cares about.
+type NetworkConfigList struct {
+	CNIVersion string      `json:"cniVersion,omitempty"`
+	Name       string      `json:"name"`
+	Plugins    []CNIPlugin `json:"plugins"`
+}
+
+// NetworkConfig is a CNI network config augmented with nerdctl-specific
+// metadata that was stashed in the conflist at creation time.
+type NetworkConfig struct {
+	*NetworkConfigList
+	NerdctlID     *string
+	NerdctlLabels *map[string]string
+	File          string
+	// Dir is the CNI config root this conflist was found under, i.e. one
+	// of CNIEnv.NetconfPaths. Useful when NetconfPaths has more than one
+	// entry and networks from other CNI consumers are mixed in.
+	Dir string
+}
+
+// NetworkList lists the CNI network configs under every directory in
+// CNIEnv.NetconfPaths, in NetconfPaths order and sorted by file name within
+// each directory. Networks with
+// the same name in an earlier directory shadow later ones, matching how
+// $NETCONFPATH precedence works for the CNI plugins themselves.
+func (e *CNIEnv) NetworkList() ([]NetworkConfig, error) {
+	seen := make(map[string]bool)
+	var configs []NetworkConfig
+	for _, dir := range e.NetconfPaths {
+		files, err := filepath.Glob(filepath.Join(dir, "*.conflist"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+		for _, f := range files {
+			l, err := loadNetworkConfigList(f)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load CNI config %q: %w", f, err)
+			}
+			if seen[l.Name] {
+				continue
+			}
+			seen[l.Name] = true
+			l.Dir = dir
+			configs = append(configs, *l)
+		}
+	}
+	return configs, nil
+}
+
+func loadNetworkConfigList(file string) (*NetworkConfig, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var raw struct {
+		NetworkConfigList
+		NerdctlID     *string            `json:"nerdctlID,omitempty"`
+		NerdctlLabels *map[string]string `json:"nerdctlLabels,omitempty"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	return &NetworkConfig{
+		NetworkConfigList: &raw.NetworkConfigList,
+		NerdctlID:         raw.NerdctlID,
+		NerdctlLabels:     raw.NerdctlLabels,
+		File:              file,
+	}, nil
+}